@@ -0,0 +1,46 @@
+package orchestrator
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors returned by Orchestrator implementations, so callers can
+// branch on failure mode with errors.Is / errors.As instead of matching
+// error strings.
+var (
+	// ErrWrongHost is wrapped by HostMismatchError, returned when a Request
+	// targets a host other than the one handling it.
+	ErrWrongHost = errors.New("incorrect host")
+	// ErrMissingField is returned when a Request is missing a field the
+	// operation requires.
+	ErrMissingField = errors.New("missing required field")
+	// ErrInstanceNotFound is returned when an operation references an
+	// instance that does not exist.
+	ErrInstanceNotFound = errors.New("instance not found")
+	// ErrDuplicateInstance is returned when creating an instance whose name
+	// is already in use.
+	ErrDuplicateInstance = errors.New("duplicate instance")
+	// ErrPartitioned is returned by a FaultPolicy that simulates a network
+	// partition to the requested host.
+	ErrPartitioned = errors.New("simulated network partition")
+	// ErrInjectedFault is returned by a FaultPolicy that simulates a crash
+	// during an otherwise successful operation.
+	ErrInjectedFault = errors.New("injected fault")
+)
+
+// HostMismatchError reports that a Request named a host other than the one
+// handling it. It wraps ErrWrongHost so callers can test for it with
+// errors.Is without caring about the specific hosts involved.
+type HostMismatchError struct {
+	Requested string
+	Current   string
+}
+
+func (e *HostMismatchError) Error() string {
+	return fmt.Sprintf("incorrect host, requested %v, current %v", e.Requested, e.Current)
+}
+
+func (e *HostMismatchError) Unwrap() error {
+	return ErrWrongHost
+}
@@ -0,0 +1,23 @@
+package orchestrator
+
+import (
+	"github.com/yasker/lm-rewrite/types"
+)
+
+// EventType identifies what happened to an instance in an InstanceEvent.
+type EventType string
+
+const (
+	EventCreated = EventType("created")
+	EventStarted = EventType("started")
+	EventStopped = EventType("stopped")
+	EventRemoved = EventType("removed")
+)
+
+// InstanceEvent describes a single change to an instance's state, as
+// streamed by the orchsim simulator's Watch method. The Orchestrator
+// interface itself declares no Watch method.
+type InstanceEvent struct {
+	Type EventType
+	Info types.InstanceInfo
+}
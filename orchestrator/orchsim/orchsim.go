@@ -1,18 +1,159 @@
 package orchsim
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"math/rand"
 	"sync"
+	"time"
 
 	"github.com/yasker/lm-rewrite/orchestrator"
 	"github.com/yasker/lm-rewrite/types"
 	"github.com/yasker/lm-rewrite/util"
 )
 
+// watchEventBuffer is how many unconsumed InstanceEvents a Watch subscriber
+// may accumulate before further sends to it are dropped.
+const watchEventBuffer = 16
+
+// snapshotSchemaVersion is bumped whenever the shape of snapshotEnvelope
+// changes, so Restore can reject a snapshot it doesn't know how to read
+// instead of silently misinterpreting it.
+const snapshotSchemaVersion = 1
+
+// snapshotEnvelope is the serialized form of an OrchSim's state, as produced
+// by Snapshot and consumed by Restore / NewOrchestratorSimulatorFromSnapshot.
+type snapshotEnvelope struct {
+	SchemaVersion int                        `json:"schemaVersion"`
+	HostID        string                     `json:"hostID"`
+	Records       map[string]*InstanceRecord `json:"records"`
+}
+
+// faultCheckInterval is how often an OrchSim with an async RandomFaultPolicy
+// checks running instances for injected crashes.
+const faultCheckInterval = 500 * time.Millisecond
+
+const (
+	opCreateController = "CreateController"
+	opCreateReplica    = "CreateReplica"
+	opStartInstance    = "StartInstance"
+	opStopInstance     = "StopInstance"
+	opRemoveInstance   = "RemoveInstance"
+	opInspectInstance  = "InspectInstance"
+)
+
 type OrchSim struct {
 	hostID  string
 	records map[string]*InstanceRecord
 	mutex   *sync.RWMutex
+
+	fleet *Fleet
+
+	faultPolicy    FaultPolicy
+	faultEvents    chan *types.InstanceInfo
+	asyncFaultStop chan struct{}
+
+	subscribers   []chan orchestrator.InstanceEvent
+	droppedEvents uint64
+}
+
+// FaultPolicy lets tests inject faults into OrchSim's operations, so
+// longhorn-manager's reconciliation logic can be exercised against crashes,
+// network partitions and slow starts instead of only the happy path.
+//
+// BeforeOp is called before an operation runs; a non-nil error aborts the
+// operation with that error instead of performing it. AfterOp is called
+// after an operation has run, whether or not it changed any state (so also
+// after InspectInstance), with the resulting instance info (nil for
+// RemoveInstance, which has none); a non-nil error is returned to the
+// caller in place of the operation's own result.
+type FaultPolicy interface {
+	BeforeOp(op string, request *orchestrator.Request) error
+	AfterOp(op string, info *types.InstanceInfo) error
+}
+
+// RandomFaultPolicy is a built-in FaultPolicy that injects crashes and
+// latency probabilistically, and can optionally crash running instances on
+// its own timer independent of any operation.
+type RandomFaultPolicy struct {
+	// CrashRate is the probability, in [0, 1], that an operation fails with
+	// an injected error after it would otherwise have succeeded.
+	CrashRate float64
+	// LatencyMs delays BeforeOp by this many milliseconds, simulating a slow
+	// start.
+	LatencyMs int
+	// PartitionedHosts names hosts whose requests always fail with a
+	// simulated network partition error.
+	PartitionedHosts map[string]bool
+	// Async, when true, makes the owning OrchSim periodically crash a
+	// random subset of its running instances in the background, clearing
+	// their IP and notifying FaultEvents(), independent of any operation
+	// being called.
+	Async bool
+}
+
+func (p *RandomFaultPolicy) BeforeOp(op string, request *orchestrator.Request) error {
+	if p.PartitionedHosts[request.HostID] {
+		return fmt.Errorf("%w: host %v", orchestrator.ErrPartitioned, request.HostID)
+	}
+	if p.LatencyMs > 0 {
+		time.Sleep(time.Duration(p.LatencyMs) * time.Millisecond)
+	}
+	return nil
+}
+
+func (p *RandomFaultPolicy) AfterOp(op string, info *types.InstanceInfo) error {
+	if p.CrashRate > 0 && rand.Float64() < p.CrashRate {
+		return fmt.Errorf("%w: during %v", orchestrator.ErrInjectedFault, op)
+	}
+	return nil
+}
+
+// Fleet is a shared, in-memory registry of the OrchSim instances that make
+// up a simulated cluster, keyed by hostID. It lets a single test process
+// stand up several OrchSim "hosts" and have calls addressed to a remote
+// host routed to the right simulator instead of failing with a host
+// mismatch error.
+type Fleet struct {
+	mutex *sync.RWMutex
+	hosts map[string]*OrchSim
+}
+
+// NewFleet creates an empty Fleet. Hosts join it via join().
+func NewFleet() *Fleet {
+	return &Fleet{
+		mutex: &sync.RWMutex{},
+		hosts: map[string]*OrchSim{},
+	}
+}
+
+func (f *Fleet) join(s *OrchSim) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.hosts[s.hostID] = s
+}
+
+func (f *Fleet) get(hostID string) (*OrchSim, bool) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	s, ok := f.hosts[hostID]
+	return s, ok
+}
+
+// rekey moves s's entry in the fleet from oldHostID to s's current hostID,
+// used when Restore changes the hostID of an already-joined OrchSim.
+func (f *Fleet) rekey(s *OrchSim, oldHostID string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if current, ok := f.hosts[oldHostID]; ok && current == s {
+		delete(f.hosts, oldHostID)
+	}
+	f.hosts[s.hostID] = s
 }
 
 type StateType string
@@ -30,20 +171,145 @@ type InstanceRecord struct {
 }
 
 func NewOrchestratorSimulator(hostID string) (orchestrator.Orchestrator, error) {
-	return &OrchSim{
+	return NewOrchestratorSimulatorInFleet(hostID, NewFleet())
+}
+
+// NewOrchestratorSimulatorInFleet creates an OrchSim for hostID and joins it
+// to fleet, so that requests addressed to any other host already in fleet
+// are transparently routed to that host's simulator. Callers that want to
+// simulate a multi-host cluster create one shared Fleet and call this for
+// each hostID.
+func NewOrchestratorSimulatorInFleet(hostID string, fleet *Fleet) (orchestrator.Orchestrator, error) {
+	s := &OrchSim{
 		hostID:  hostID,
 		records: map[string]*InstanceRecord{},
 		mutex:   &sync.RWMutex{},
-	}, nil
+		fleet:   fleet,
+	}
+	fleet.join(s)
+	return s, nil
+}
+
+// NewOrchestratorSimulatorFromSnapshot recreates an OrchSim from data
+// previously produced by Snapshot, joining it to a fleet of its own. Use
+// NewOrchestratorSimulatorInFleet followed by Restore instead if the
+// restored host needs to rejoin an existing fleet.
+func NewOrchestratorSimulatorFromSnapshot(data []byte) (orchestrator.Orchestrator, error) {
+	var envelope snapshotEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, err
+	}
+	if envelope.SchemaVersion != snapshotSchemaVersion {
+		return nil, fmt.Errorf("unsupported snapshot schema version %v", envelope.SchemaVersion)
+	}
+
+	records := envelope.Records
+	if records == nil {
+		records = map[string]*InstanceRecord{}
+	}
+	s := &OrchSim{
+		hostID:  envelope.HostID,
+		records: records,
+		mutex:   &sync.RWMutex{},
+	}
+	s.fleet = NewFleet()
+	s.fleet.join(s)
+	return s, nil
+}
+
+// Snapshot serializes the simulator's records and hostID into a versioned
+// envelope, taking the mutex so it is safe to call while other goroutines
+// are driving the simulator.
+func (s *OrchSim) Snapshot() ([]byte, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return json.Marshal(snapshotEnvelope{
+		SchemaVersion: snapshotSchemaVersion,
+		HostID:        s.hostID,
+		Records:       s.records,
+	})
+}
+
+// Restore replaces the simulator's hostID and records with those encoded in
+// data, as produced by Snapshot. It takes the mutex so it is safe to call
+// while other goroutines are driving the simulator. If the snapshot's hostID
+// differs from the simulator's current one, Restore also re-keys it in its
+// Fleet so peer routing keeps finding it under the restored hostID.
+func (s *OrchSim) Restore(data []byte) error {
+	var envelope snapshotEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return err
+	}
+	if envelope.SchemaVersion != snapshotSchemaVersion {
+		return fmt.Errorf("unsupported snapshot schema version %v", envelope.SchemaVersion)
+	}
+
+	records := envelope.Records
+	if records == nil {
+		records = map[string]*InstanceRecord{}
+	}
+
+	s.mutex.Lock()
+	oldHostID := s.hostID
+	s.hostID = envelope.HostID
+	s.records = records
+	s.mutex.Unlock()
+
+	if s.fleet != nil && envelope.HostID != oldHostID {
+		s.fleet.rekey(s, oldHostID)
+	}
+	return nil
+}
+
+// SaveToFile writes a Snapshot of the simulator to path, so a long-running
+// integration test can checkpoint state, kill the process, and later resume
+// with LoadFromFile.
+func (s *OrchSim) SaveToFile(path string) error {
+	data, err := s.Snapshot()
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0600)
+}
+
+// LoadFromFile restores the simulator's state from a snapshot previously
+// written by SaveToFile.
+func (s *OrchSim) LoadFromFile(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return s.Restore(data)
+}
+
+// peer returns the OrchSim responsible for hostID if it isn't the local
+// host, so callers can route a request to it instead of handling it
+// locally.
+func (s *OrchSim) peer(hostID string) (*OrchSim, error) {
+	if hostID == s.GetCurrentHostID() {
+		return nil, nil
+	}
+	peer, ok := s.fleet.get(hostID)
+	if !ok {
+		return nil, &orchestrator.HostMismatchError{Requested: hostID, Current: s.GetCurrentHostID()}
+	}
+	return peer, nil
 }
 
 func (s *OrchSim) CreateController(request *orchestrator.Request) (*types.ControllerInfo, error) {
-	if request.HostID != s.GetCurrentHostID() {
-		return nil, fmt.Errorf("incorrect host, requested %v, current %v", request.HostID,
-			s.GetCurrentHostID())
+	peer, err := s.peer(request.HostID)
+	if err != nil {
+		return nil, err
+	}
+	if peer != nil {
+		return peer.CreateController(request)
+	}
+	if err := s.beforeOp(opCreateController, request); err != nil {
+		return nil, err
 	}
 	if request.InstanceName == "" {
-		return nil, fmt.Errorf("missing required field %+v", request)
+		return nil, fmt.Errorf("%w: %+v", orchestrator.ErrMissingField, request)
 	}
 
 	instance := &InstanceRecord{
@@ -59,24 +325,26 @@ func (s *OrchSim) CreateController(request *orchestrator.Request) (*types.Contro
 	if err := s.createRecord(instance); err != nil {
 		return nil, err
 	}
-	return &types.ControllerInfo{
-		InstanceInfo: types.InstanceInfo{
-			ID:      instance.ID,
-			Name:    instance.Name,
-			HostID:  s.GetCurrentHostID(),
-			Address: instance.IP,
-			Running: instance.State == StateRunning,
-		},
-	}, nil
+	info := s.instanceInfo(instance)
+	if err := s.afterOp(opCreateController, &info); err != nil {
+		return nil, err
+	}
+	return &types.ControllerInfo{InstanceInfo: info}, nil
 }
 
 func (s *OrchSim) CreateReplica(request *orchestrator.Request) (*types.ReplicaInfo, error) {
-	if request.HostID != s.GetCurrentHostID() {
-		return nil, fmt.Errorf("incorrect host, requested %v, current %v", request.HostID,
-			s.GetCurrentHostID())
+	peer, err := s.peer(request.HostID)
+	if err != nil {
+		return nil, err
+	}
+	if peer != nil {
+		return peer.CreateReplica(request)
+	}
+	if err := s.beforeOp(opCreateReplica, request); err != nil {
+		return nil, err
 	}
 	if request.InstanceName == "" {
-		return nil, fmt.Errorf("missing required field %+v", request)
+		return nil, fmt.Errorf("%w: %+v", orchestrator.ErrMissingField, request)
 	}
 
 	instance := &InstanceRecord{
@@ -91,14 +359,12 @@ func (s *OrchSim) CreateReplica(request *orchestrator.Request) (*types.ReplicaIn
 	if err := s.createRecord(instance); err != nil {
 		return nil, err
 	}
+	info := s.instanceInfo(instance)
+	if err := s.afterOp(opCreateReplica, &info); err != nil {
+		return nil, err
+	}
 	return &types.ReplicaInfo{
-		InstanceInfo: types.InstanceInfo{
-			ID:      instance.ID,
-			Name:    instance.Name,
-			HostID:  s.GetCurrentHostID(),
-			Address: instance.IP,
-			Running: instance.State == StateRunning,
-		},
+		InstanceInfo: info,
 
 		Mode:         "",
 		BadTimestamp: "",
@@ -106,13 +372,19 @@ func (s *OrchSim) CreateReplica(request *orchestrator.Request) (*types.ReplicaIn
 }
 
 func (s *OrchSim) StartInstance(request *orchestrator.Request) (*types.InstanceInfo, error) {
-	if request.HostID != s.GetCurrentHostID() {
-		return nil, fmt.Errorf("incorrect host, requested %v, current %v", request.HostID,
-			s.GetCurrentHostID())
+	peer, err := s.peer(request.HostID)
+	if err != nil {
+		return nil, err
+	}
+	if peer != nil {
+		return peer.StartInstance(request)
+	}
+	if err := s.beforeOp(opStartInstance, request); err != nil {
+		return nil, err
 	}
 
 	if request.InstanceName == "" {
-		return nil, fmt.Errorf("missing required field %+v", request)
+		return nil, fmt.Errorf("%w: %+v", orchestrator.ErrMissingField, request)
 	}
 
 	s.mutex.Lock()
@@ -129,22 +401,26 @@ func (s *OrchSim) StartInstance(request *orchestrator.Request) (*types.InstanceI
 			return nil, err
 		}
 	}
-	return &types.InstanceInfo{
-		ID:      instance.ID,
-		Name:    instance.Name,
-		HostID:  s.GetCurrentHostID(),
-		Address: instance.IP,
-		Running: instance.State == StateRunning,
-	}, nil
+	info := s.instanceInfo(instance)
+	if err := s.afterOp(opStartInstance, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
 }
 
 func (s *OrchSim) StopInstance(request *orchestrator.Request) (*types.InstanceInfo, error) {
-	if request.HostID != s.GetCurrentHostID() {
-		return nil, fmt.Errorf("incorrect host, requested %v, current %v", request.HostID,
-			s.GetCurrentHostID())
+	peer, err := s.peer(request.HostID)
+	if err != nil {
+		return nil, err
+	}
+	if peer != nil {
+		return peer.StopInstance(request)
+	}
+	if err := s.beforeOp(opStopInstance, request); err != nil {
+		return nil, err
 	}
 	if request.InstanceName == "" {
-		return nil, fmt.Errorf("missing required field %+v", request)
+		return nil, fmt.Errorf("%w: %+v", orchestrator.ErrMissingField, request)
 	}
 
 	s.mutex.Lock()
@@ -161,33 +437,47 @@ func (s *OrchSim) StopInstance(request *orchestrator.Request) (*types.InstanceIn
 			return nil, err
 		}
 	}
-	return &types.InstanceInfo{
-		ID:      instance.ID,
-		Name:    instance.Name,
-		HostID:  s.GetCurrentHostID(),
-		Address: instance.IP,
-		Running: instance.State == StateRunning,
-	}, nil
+	info := s.instanceInfo(instance)
+	if err := s.afterOp(opStopInstance, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
 }
 
 func (s *OrchSim) RemoveInstance(request *orchestrator.Request) error {
-	if request.HostID != s.GetCurrentHostID() {
-		return fmt.Errorf("incorrect host, requested %v, current %v", request.HostID,
-			s.GetCurrentHostID())
+	peer, err := s.peer(request.HostID)
+	if err != nil {
+		return err
+	}
+	if peer != nil {
+		return peer.RemoveInstance(request)
+	}
+	if err := s.beforeOp(opRemoveInstance, request); err != nil {
+		return err
 	}
+
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
-	return s.removeRecord(request.InstanceName)
+	if err := s.removeRecord(request.InstanceName); err != nil {
+		return err
+	}
+	return s.afterOp(opRemoveInstance, nil)
 }
 
 func (s *OrchSim) InspectInstance(request *orchestrator.Request) (*types.InstanceInfo, error) {
-	if request.HostID != s.GetCurrentHostID() {
-		return nil, fmt.Errorf("incorrect host, requested %v, current %v", request.HostID,
-			s.GetCurrentHostID())
+	peer, err := s.peer(request.HostID)
+	if err != nil {
+		return nil, err
+	}
+	if peer != nil {
+		return peer.InspectInstance(request)
+	}
+	if err := s.beforeOp(opInspectInstance, request); err != nil {
+		return nil, err
 	}
 	if request.InstanceName == "" {
-		return nil, fmt.Errorf("missing required field %+v", request)
+		return nil, fmt.Errorf("%w: %+v", orchestrator.ErrMissingField, request)
 	}
 
 	s.mutex.RLock()
@@ -197,50 +487,216 @@ func (s *OrchSim) InspectInstance(request *orchestrator.Request) (*types.Instanc
 	if err != nil {
 		return nil, err
 	}
-	return &types.InstanceInfo{
+	info := s.instanceInfo(instance)
+	if err := s.afterOp(opInspectInstance, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+func (s *OrchSim) GetCurrentHostID() string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.hostID
+}
+
+// SetFaultPolicy installs policy as the FaultPolicy consulted on every
+// operation. Passing nil clears the policy. If policy is a *RandomFaultPolicy
+// with Async set, SetFaultPolicy also starts a background goroutine that
+// periodically crashes a random subset of running instances; replacing or
+// clearing the policy stops any previously running goroutine.
+func (s *OrchSim) SetFaultPolicy(policy FaultPolicy) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.asyncFaultStop != nil {
+		close(s.asyncFaultStop)
+		s.asyncFaultStop = nil
+	}
+	s.faultPolicy = policy
+
+	if random, ok := policy.(*RandomFaultPolicy); ok && random.Async {
+		s.asyncFaultStop = make(chan struct{})
+		go s.runAsyncFaults(random, s.asyncFaultStop)
+	}
+}
+
+// FaultEvents returns the channel on which instances crashed by an async
+// RandomFaultPolicy are reported. The channel is created lazily and is never
+// closed.
+func (s *OrchSim) FaultEvents() <-chan *types.InstanceInfo {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.faultEvents == nil {
+		s.faultEvents = make(chan *types.InstanceInfo, 16)
+	}
+	return s.faultEvents
+}
+
+func (s *OrchSim) runAsyncFaults(policy *RandomFaultPolicy, stop chan struct{}) {
+	ticker := time.NewTicker(faultCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			s.crashRandomInstances(policy)
+		}
+	}
+}
+
+func (s *OrchSim) crashRandomInstances(policy *RandomFaultPolicy) {
+	s.mutex.Lock()
+	var crashed []*types.InstanceInfo
+	for _, instance := range s.records {
+		if instance.State != StateRunning || rand.Float64() >= policy.CrashRate {
+			continue
+		}
+		instance.State = StateStopped
+		instance.IP = ""
+		// Route through updateRecord, not a direct field mutation, so Watch
+		// subscribers see the same Stopped event an op-triggered StopInstance
+		// would have published.
+		if err := s.updateRecord(instance); err != nil {
+			continue
+		}
+		info := s.instanceInfo(instance)
+		crashed = append(crashed, &info)
+	}
+	events := s.faultEvents
+	s.mutex.Unlock()
+
+	if events == nil {
+		return
+	}
+	for _, info := range crashed {
+		select {
+		case events <- info:
+		default:
+		}
+	}
+}
+
+// Watch streams an InstanceEvent for every create, start, stop and remove
+// that the simulator processes from now on. Sends to a slow consumer are
+// dropped rather than blocking the operation that produced them; the
+// returned channel is closed once ctx is done.
+func (s *OrchSim) Watch(ctx context.Context) (<-chan orchestrator.InstanceEvent, error) {
+	ch := make(chan orchestrator.InstanceEvent, watchEventBuffer)
+
+	s.mutex.Lock()
+	s.subscribers = append(s.subscribers, ch)
+	s.mutex.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		for i, sub := range s.subscribers {
+			if sub == ch {
+				s.subscribers = append(s.subscribers[:i], s.subscribers[i+1:]...)
+				break
+			}
+		}
+		close(ch)
+	}()
+
+	return ch, nil
+}
+
+// publish fans event out to every Watch subscriber. Must be locked.
+func (s *OrchSim) publish(event orchestrator.InstanceEvent) {
+	for _, sub := range s.subscribers {
+		select {
+		case sub <- event:
+		default:
+			s.droppedEvents++
+		}
+	}
+}
+
+// instanceInfo builds the public view of instance. Must be locked.
+func (s *OrchSim) instanceInfo(instance *InstanceRecord) types.InstanceInfo {
+	return types.InstanceInfo{
 		ID:      instance.ID,
 		Name:    instance.Name,
-		HostID:  s.GetCurrentHostID(),
+		HostID:  s.hostID,
 		Address: instance.IP,
 		Running: instance.State == StateRunning,
-	}, nil
+	}
 }
 
-func (s *OrchSim) GetCurrentHostID() string {
-	return s.hostID
+// beforeOp consults the active FaultPolicy, if any, before op runs. Callers
+// must not hold s.mutex: beforeOp acquires it itself.
+func (s *OrchSim) beforeOp(op string, request *orchestrator.Request) error {
+	s.mutex.RLock()
+	policy := s.faultPolicy
+	s.mutex.RUnlock()
+
+	if policy == nil {
+		return nil
+	}
+	return policy.BeforeOp(op, request)
+}
+
+// afterOp consults the active FaultPolicy, if any, after op has run, whether
+// or not it mutated state. Must be called with s.mutex already held (by the
+// caller's own Lock/RLock), since the op that precedes it needs the lock held
+// throughout; s.mutex is a sync.RWMutex, so re-acquiring it here would
+// deadlock.
+func (s *OrchSim) afterOp(op string, info *types.InstanceInfo) error {
+	if s.faultPolicy == nil {
+		return nil
+	}
+	return s.faultPolicy.AfterOp(op, info)
 }
 
 // Must be locked
 func (s *OrchSim) createRecord(instance *InstanceRecord) error {
 	if s.records[instance.Name] != nil {
-		return fmt.Errorf("duplicate instance with name %v", instance.Name)
+		return fmt.Errorf("%w: %v", orchestrator.ErrDuplicateInstance, instance.Name)
 	}
 	s.records[instance.Name] = instance
+	s.publish(orchestrator.InstanceEvent{Type: orchestrator.EventCreated, Info: s.instanceInfo(instance)})
 	return nil
 }
 
 // Must be locked
 func (s *OrchSim) updateRecord(instance *InstanceRecord) error {
 	if s.records[instance.Name] == nil {
-		return fmt.Errorf("unable to find instance with name %v", instance.Name)
+		return fmt.Errorf("%w: %v", orchestrator.ErrInstanceNotFound, instance.Name)
 	}
 	s.records[instance.Name] = instance
+
+	eventType := orchestrator.EventStopped
+	if instance.State == StateRunning {
+		eventType = orchestrator.EventStarted
+	}
+	s.publish(orchestrator.InstanceEvent{Type: eventType, Info: s.instanceInfo(instance)})
 	return nil
 }
 
 // Must be locked
 func (s *OrchSim) getRecord(instanceName string) (*InstanceRecord, error) {
 	if s.records[instanceName] == nil {
-		return nil, fmt.Errorf("unable to find instance %v", instanceName)
+		return nil, fmt.Errorf("%w: %v", orchestrator.ErrInstanceNotFound, instanceName)
 	}
 	return s.records[instanceName], nil
 }
 
 // Must be locked
 func (s *OrchSim) removeRecord(instanceName string) error {
-	if s.records[instanceName] == nil {
-		return fmt.Errorf("unable to find instance %v", instanceName)
+	instance := s.records[instanceName]
+	if instance == nil {
+		return fmt.Errorf("%w: %v", orchestrator.ErrInstanceNotFound, instanceName)
 	}
 	delete(s.records, instanceName)
+	s.publish(orchestrator.InstanceEvent{Type: orchestrator.EventRemoved, Info: s.instanceInfo(instance)})
 	return nil
 }
\ No newline at end of file
@@ -0,0 +1,202 @@
+package orchsim
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yasker/lm-rewrite/orchestrator"
+	"github.com/yasker/lm-rewrite/types"
+)
+
+func TestFleetRoutesRequestToPeerHost(t *testing.T) {
+	fleet := NewFleet()
+	hostA, err := NewOrchestratorSimulatorInFleet("host-a", fleet)
+	if err != nil {
+		t.Fatalf("NewOrchestratorSimulatorInFleet(host-a): %v", err)
+	}
+	hostB, err := NewOrchestratorSimulatorInFleet("host-b", fleet)
+	if err != nil {
+		t.Fatalf("NewOrchestratorSimulatorInFleet(host-b): %v", err)
+	}
+
+	info, err := hostA.CreateController(&orchestrator.Request{HostID: "host-b", InstanceName: "vol-1"})
+	if err != nil {
+		t.Fatalf("CreateController against host-b via host-a: %v", err)
+	}
+	if info.HostID != "host-b" {
+		t.Fatalf("expected the instance to be created on host-b, got %v", info.HostID)
+	}
+
+	got, err := hostB.InspectInstance(&orchestrator.Request{HostID: "host-b", InstanceName: "vol-1"})
+	if err != nil {
+		t.Fatalf("InspectInstance directly on host-b: %v", err)
+	}
+	if got.ID != info.ID {
+		t.Fatalf("expected host-b to see the instance host-a's routed request created, got %+v", got)
+	}
+}
+
+// noopFaultPolicy is a FaultPolicy that never injects a fault; it exists so
+// tests can exercise the BeforeOp/AfterOp call path without randomness.
+type noopFaultPolicy struct{}
+
+func (noopFaultPolicy) BeforeOp(op string, request *orchestrator.Request) error { return nil }
+func (noopFaultPolicy) AfterOp(op string, info *types.InstanceInfo) error       { return nil }
+
+func TestCreateControllerWithFaultPolicyDoesNotDeadlock(t *testing.T) {
+	s, err := NewOrchestratorSimulator("host-1")
+	if err != nil {
+		t.Fatalf("NewOrchestratorSimulator: %v", err)
+	}
+	sim := s.(*OrchSim)
+	sim.SetFaultPolicy(noopFaultPolicy{})
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := sim.CreateController(&orchestrator.Request{HostID: "host-1", InstanceName: "vol-1"})
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("CreateController: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("CreateController deadlocked with a fault policy installed")
+	}
+}
+
+// recvEvent waits up to a second for the next event on events, failing the
+// test instead of hanging forever if none arrives.
+func recvEvent(t *testing.T, events <-chan orchestrator.InstanceEvent) orchestrator.InstanceEvent {
+	t.Helper()
+
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for an InstanceEvent")
+		return orchestrator.InstanceEvent{}
+	}
+}
+
+func TestAsyncCrashPublishesWatchEvent(t *testing.T) {
+	s, err := NewOrchestratorSimulator("host-1")
+	if err != nil {
+		t.Fatalf("NewOrchestratorSimulator: %v", err)
+	}
+	sim := s.(*OrchSim)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := sim.Watch(ctx)
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+
+	if _, err := sim.CreateController(&orchestrator.Request{HostID: "host-1", InstanceName: "vol-1"}); err != nil {
+		t.Fatalf("CreateController: %v", err)
+	}
+	if event := recvEvent(t, events); event.Type != orchestrator.EventCreated {
+		t.Fatalf("expected a Created event, got %v", event.Type)
+	}
+
+	sim.crashRandomInstances(&RandomFaultPolicy{CrashRate: 1})
+
+	if event := recvEvent(t, events); event.Type != orchestrator.EventStopped {
+		t.Fatalf("expected a Stopped event, got %v", event.Type)
+	}
+}
+
+func TestInspectInstanceObservesPartition(t *testing.T) {
+	s, err := NewOrchestratorSimulator("host-1")
+	if err != nil {
+		t.Fatalf("NewOrchestratorSimulator: %v", err)
+	}
+	sim := s.(*OrchSim)
+
+	if _, err := sim.CreateController(&orchestrator.Request{HostID: "host-1", InstanceName: "vol-1"}); err != nil {
+		t.Fatalf("CreateController: %v", err)
+	}
+
+	sim.SetFaultPolicy(&RandomFaultPolicy{PartitionedHosts: map[string]bool{"host-1": true}})
+
+	if _, err := sim.InspectInstance(&orchestrator.Request{HostID: "host-1", InstanceName: "vol-1"}); err == nil {
+		t.Fatal("expected InspectInstance to observe the simulated partition, got nil error")
+	}
+}
+
+func TestErrorsAreTypedSentinels(t *testing.T) {
+	s, err := NewOrchestratorSimulator("host-1")
+	if err != nil {
+		t.Fatalf("NewOrchestratorSimulator: %v", err)
+	}
+	sim := s.(*OrchSim)
+
+	_, err = sim.CreateController(&orchestrator.Request{HostID: "host-2", InstanceName: "vol-1"})
+	var hostErr *orchestrator.HostMismatchError
+	if !errors.As(err, &hostErr) {
+		t.Fatalf("expected errors.As to find a *HostMismatchError, got %v", err)
+	}
+	if !errors.Is(err, orchestrator.ErrWrongHost) {
+		t.Fatalf("expected errors.Is(err, ErrWrongHost) to hold, got %v", err)
+	}
+
+	_, err = sim.CreateController(&orchestrator.Request{HostID: "host-1"})
+	if !errors.Is(err, orchestrator.ErrMissingField) {
+		t.Fatalf("expected errors.Is(err, ErrMissingField) to hold, got %v", err)
+	}
+
+	if _, err := sim.CreateController(&orchestrator.Request{HostID: "host-1", InstanceName: "vol-1"}); err != nil {
+		t.Fatalf("CreateController: %v", err)
+	}
+	_, err = sim.CreateController(&orchestrator.Request{HostID: "host-1", InstanceName: "vol-1"})
+	if !errors.Is(err, orchestrator.ErrDuplicateInstance) {
+		t.Fatalf("expected errors.Is(err, ErrDuplicateInstance) to hold, got %v", err)
+	}
+
+	_, err = sim.StartInstance(&orchestrator.Request{HostID: "host-1", InstanceName: "missing"})
+	if !errors.Is(err, orchestrator.ErrInstanceNotFound) {
+		t.Fatalf("expected errors.Is(err, ErrInstanceNotFound) to hold, got %v", err)
+	}
+}
+
+func TestRestoreRekeysFleetOnHostIDChange(t *testing.T) {
+	s, err := NewOrchestratorSimulator("host-1")
+	if err != nil {
+		t.Fatalf("NewOrchestratorSimulator: %v", err)
+	}
+	sim := s.(*OrchSim)
+
+	if _, err := sim.CreateController(&orchestrator.Request{HostID: "host-1", InstanceName: "vol-1"}); err != nil {
+		t.Fatalf("CreateController: %v", err)
+	}
+	snapshot, err := sim.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot: %v", err)
+	}
+
+	var envelope snapshotEnvelope
+	if err := json.Unmarshal(snapshot, &envelope); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	envelope.HostID = "host-2"
+	renamed, err := json.Marshal(envelope)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if err := sim.Restore(renamed); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+
+	if _, ok := sim.fleet.get("host-1"); ok {
+		t.Fatal("fleet still routes the old hostID to the renamed host")
+	}
+	if peer, ok := sim.fleet.get("host-2"); !ok || peer != sim {
+		t.Fatal("fleet does not route the restored hostID to the renamed host")
+	}
+}